@@ -0,0 +1,90 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// hostKeyCallback builds a HostKeyCallback backed by defaults.KnownHostsFile.
+// Unknown hosts are presented to the user with their OpenSSH-style
+// fingerprint for accept/reject/abort; accepted keys are appended to the
+// known_hosts file. A key that doesn't match what's on file is refused.
+func hostKeyCallback(defaults Defaults) (ssh.HostKeyCallback, error) {
+	path := defaults.KnownHostsFile
+	if path == "" {
+		path = defaultDefaults().KnownHostsFile
+	}
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		if _, err := os.Create(path); err != nil {
+			return nil, fmt.Errorf("failed to create known_hosts file: %w", err)
+		}
+	}
+
+	base, err := knownhosts.New(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load known_hosts: %w", err)
+	}
+
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		err := base(hostname, remote, key)
+		if err == nil {
+			return nil
+		}
+
+		var keyErr *knownhosts.KeyError
+		if !errors.As(err, &keyErr) {
+			return err
+		}
+
+		if len(keyErr.Want) > 0 {
+			fmt.Printf("WARNING: REMOTE HOST IDENTIFICATION HAS CHANGED for %s!\n", hostname)
+			fmt.Printf("It is possible someone is eavesdropping, or the host key has just been changed.\n")
+			fmt.Printf("Offending key fingerprint: %s\n", ssh.FingerprintSHA256(key))
+			return fmt.Errorf("host key mismatch for %s, refusing to connect", hostname)
+		}
+
+		return promptAcceptUnknownHostKey(path, hostname, key)
+	}, nil
+}
+
+// promptAcceptUnknownHostKey shows the key's fingerprint and asks the user
+// to accept, reject, or abort; accepting appends the key to known_hosts.
+// Guarded by stdinMu so concurrent dials (e.g. from runOnHosts) don't
+// interleave their prompts and answers on the shared stdin.
+func promptAcceptUnknownHostKey(path, hostname string, key ssh.PublicKey) error {
+	stdinMu.Lock()
+	defer stdinMu.Unlock()
+
+	fmt.Printf("The authenticity of host '%s' can't be established.\n", hostname)
+	fmt.Printf("%s key fingerprint is %s.\n", key.Type(), ssh.FingerprintSHA256(key))
+	fmt.Print("Are you sure you want to continue connecting (yes/no/[abort])? ")
+
+	switch readUserInput() {
+	case "yes":
+		return appendKnownHost(path, hostname, key)
+	default:
+		return fmt.Errorf("host key for %s not accepted, aborting", hostname)
+	}
+}
+
+// appendKnownHost writes a new known_hosts line for hostname/key.
+func appendKnownHost(path, hostname string, key ssh.PublicKey) error {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open known_hosts: %w", err)
+	}
+	defer file.Close()
+
+	line := knownhosts.Line([]string{hostname}, key)
+	if _, err := fmt.Fprintln(file, line); err != nil {
+		return fmt.Errorf("failed to update known_hosts: %w", err)
+	}
+
+	return nil
+}
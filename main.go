@@ -2,6 +2,7 @@ package main
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/fatih/color"
@@ -15,20 +16,30 @@ import (
 	"path/filepath"
 	"runtime"
 	"strconv"
+	"strings"
+	"sync"
 )
 
 type Connection struct {
-	Name     string `json:"name"`
-	Host     string `json:"host"`
-	Port     int    `json:"port"`
-	Username string `json:"username"`
-	SSHKey   string `json:"ssh_key"`
+	Name        string    `json:"name"`
+	Host        string    `json:"host"`
+	Port        int       `json:"port"`
+	Username    string    `json:"username"`
+	SSHKey      SecretRef `json:"ssh_key"`
+	PasswordRef SecretRef `json:"password_ref,omitempty"`
+	Tunnels     []Tunnel  `json:"tunnels,omitempty"`
+	Tags        []string  `json:"tags,omitempty"`
 }
 
 type Defaults struct {
-	Port     int    `json:"port"`
-	Username string `json:"username"`
-	SSHKey   string `json:"ssh_key"`
+	Port              int      `json:"port"`
+	Username          string   `json:"username"`
+	SSHKey            string   `json:"ssh_key"`
+	UseNativeSSH      bool     `json:"use_native_ssh"`
+	KnownHostsFile    string   `json:"known_hosts_file"`
+	HostKeyAlgorithms []string `json:"host_key_algorithms,omitempty"`
+	MaxParallel       int      `json:"max_parallel"`
+	SecretCommand     string   `json:"secret_command,omitempty"`
 }
 
 var (
@@ -54,6 +65,13 @@ func printHeading() {
 func main() {
 	setupConfigPaths()
 
+	for _, arg := range os.Args[1:] {
+		if arg == "--import-ssh-config" {
+			importSSHConfigHosts()
+			return
+		}
+	}
+
 	printHeading() // Call the function to print the heading
 
 	for {
@@ -69,6 +87,14 @@ func main() {
 		case "4":
 			editDefaultSettings()
 		case "5":
+			manageTunnels()
+		case "6":
+			importSSHConfigHosts()
+		case "7":
+			browseFiles()
+		case "8":
+			runCommandOnMultipleHosts()
+		case "9":
 			fmt.Println("Exiting...")
 			return
 		default:
@@ -114,6 +140,11 @@ func readUserInput() string {
 	return input[:len(input)-1]
 }
 
+// stdinMu serializes interactive stdin prompts (host key confirmation,
+// password/passphrase entry) so that concurrent dials from runOnHosts don't
+// interleave their prompts and reads on the shared os.Stdin.
+var stdinMu sync.Mutex
+
 // Print main menu
 func printMainMenu() {
 	fmt.Println(`
@@ -121,7 +152,11 @@ func printMainMenu() {
 2. Add connection
 3. Delete connection
 4. Edit default settings
-5. Exit
+5. Manage tunnels
+6. Import SSH config hosts
+7. Browse files
+8. Run command on multiple hosts
+9. Exit
 Please enter your choice:`)
 }
 
@@ -155,6 +190,29 @@ func loadConnections() ([]Connection, error) {
 	return connections, nil
 }
 
+// Save connections to file
+func saveConnections(connections []Connection) error {
+	file, err := os.Create(connectionsFile)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return json.NewEncoder(file).Encode(connections)
+}
+
+// defaultDefaults returns the Defaults used to seed a fresh config file.
+func defaultDefaults() Defaults {
+	return Defaults{
+		Port:           22,
+		Username:       "root",
+		SSHKey:         filepath.Join(os.Getenv("HOME"), ".ssh", "id_rsa"),
+		UseNativeSSH:   true,
+		KnownHostsFile: filepath.Join(os.Getenv("HOME"), ".ssh", "known_hosts"),
+		MaxParallel:    5,
+	}
+}
+
 // Load default settings from file
 func loadDefaults() (Defaults, error) {
 	var defaults Defaults
@@ -162,7 +220,7 @@ func loadDefaults() (Defaults, error) {
 	// Check if file exists
 	if _, err := os.Stat(defaultsFile); os.IsNotExist(err) {
 		// Initialize defaults and save them to file
-		defaults = Defaults{Port: 22, Username: "root", SSHKey: filepath.Join(os.Getenv("HOME"), ".ssh", "id_rsa")}
+		defaults = defaultDefaults()
 		defaultsJson, err := json.Marshal(defaults)
 		if err != nil {
 			return defaults, err
@@ -184,7 +242,7 @@ func loadDefaults() (Defaults, error) {
 	if err != nil {
 		// Handle empty or improperly formatted file
 		if err == io.EOF {
-			defaults = Defaults{Port: 22, Username: "root", SSHKey: filepath.Join(os.Getenv("HOME"), ".ssh", "id_rsa")}
+			defaults = defaultDefaults()
 			defaultsJson, err := json.Marshal(defaults)
 			if err != nil {
 				return defaults, err
@@ -212,22 +270,13 @@ func saveDefaults(defaults Defaults) error {
 	return json.NewEncoder(file).Encode(defaults)
 }
 
-// List connections and prompt to connect
-func listConnections() {
-	connections, err := loadConnections()
-	if err != nil {
-		fmt.Println("Error loading connections:", err)
-		return
-	}
-
-	if len(connections) == 0 {
-		fmt.Println("No connections found.")
-		return
-	}
-
-	// Create a new table writer
+// printConnectionsTable renders the colorized connections table without
+// prompting for anything, so callers that just need to show the list (e.g.
+// to pick a connection for some other feature) don't risk accidentally
+// opening a live SSH session via listConnections' own prompt.
+func printConnectionsTable(connections []Connection) {
 	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"#", "Name", "Host", "Port", "Username", "SSH Key"})
+	table.SetHeader([]string{"#", "Name", "Host", "Port", "Username", "SSH Key", "Tags"})
 
 	// Define color functions
 	indexColor := color.New(color.FgCyan).SprintFunc()
@@ -236,6 +285,7 @@ func listConnections() {
 	portColor := color.New(color.FgYellow).SprintFunc()
 	usernameColor := color.New(color.FgBlue).SprintFunc()
 	sshKeyColor := color.New(color.FgRed).SprintFunc()
+	tagsColor := color.New(color.FgCyan).SprintFunc()
 
 	// Populate rows with colorized data
 	for i, conn := range connections {
@@ -246,6 +296,7 @@ func listConnections() {
 			portColor(strconv.Itoa(conn.Port)),
 			usernameColor(conn.Username),
 			sshKeyColor(conn.SSHKey),
+			tagsColor(strings.Join(conn.Tags, ",")),
 		})
 	}
 
@@ -253,6 +304,22 @@ func listConnections() {
 	table.SetAlignment(tablewriter.ALIGN_LEFT)
 	table.SetRowLine(true)
 	table.Render()
+}
+
+// List connections and prompt to connect
+func listConnections() {
+	connections, err := loadConnections()
+	if err != nil {
+		fmt.Println("Error loading connections:", err)
+		return
+	}
+
+	if len(connections) == 0 {
+		fmt.Println("No connections found.")
+		return
+	}
+
+	printConnectionsTable(connections)
 
 	// Prompt for connection selection
 	fmt.Print("Enter the number of the connection to connect, or 'b' to go back: ")
@@ -268,7 +335,14 @@ func listConnections() {
 	}
 
 	connection := connections[index-1]
-	executeSSHCommand(connection)
+
+	defaults, err := loadDefaults()
+	if err != nil {
+		fmt.Println("Error loading default settings:", err)
+		return
+	}
+
+	connectToHost(connection, defaults)
 }
 
 // Add a new connection
@@ -301,19 +375,27 @@ func addConnection() {
 		username = defaults.Username
 	}
 
-	fmt.Printf("SSH Key (default: %s): ", defaults.SSHKey)
+	fmt.Printf("SSH Key (file path, or a secret ref like keyring://ssm/my-key, env://VAR, op://vault/item/field) (default: %s): ", defaults.SSHKey)
 	sshKey := readUserInput()
 	if sshKey == "" {
 		sshKey = defaults.SSHKey
 	}
 
+	fmt.Printf("Password secret ref (optional, e.g. keyring://ssm/my-host): ")
+	passwordRef := readUserInput()
+
+	fmt.Printf("Tags (comma-separated, optional): ")
+	tags := splitCommaSeparated(readUserInput())
+
 	// Create new connection
 	newConnection := Connection{
-		Name:     name,
-		Host:     host,
-		Port:     port,
-		Username: username,
-		SSHKey:   sshKey,
+		Name:        name,
+		Host:        host,
+		Port:        port,
+		Username:    username,
+		SSHKey:      SecretRef(sshKey),
+		PasswordRef: SecretRef(passwordRef),
+		Tags:        tags,
 	}
 
 	// Load existing connections
@@ -327,18 +409,10 @@ func addConnection() {
 	connections = append(connections, newConnection)
 
 	// Save connections back to file
-	file, err := os.Create(connectionsFile)
-	if err != nil {
+	if err := saveConnections(connections); err != nil {
 		fmt.Println("Error saving connections:", err)
 		return
 	}
-	defer file.Close()
-
-	err = json.NewEncoder(file).Encode(connections)
-	if err != nil {
-		fmt.Println("Error encoding connections:", err)
-		return
-	}
 
 	fmt.Println("Connection added successfully!")
 }
@@ -356,7 +430,7 @@ func deleteConnection() {
 		return
 	}
 
-	listConnections()
+	printConnectionsTable(connections)
 
 	fmt.Print("Enter the number of the connection you want to delete: ")
 	indexStr := readUserInput()
@@ -370,18 +444,10 @@ func deleteConnection() {
 	connections = append(connections[:index-1], connections[index:]...)
 
 	// Save the updated connections back to the file
-	file, err := os.Create(connectionsFile)
-	if err != nil {
+	if err := saveConnections(connections); err != nil {
 		fmt.Println("Error saving connections:", err)
 		return
 	}
-	defer file.Close()
-
-	err = json.NewEncoder(file).Encode(connections)
-	if err != nil {
-		fmt.Println("Error encoding connections:", err)
-		return
-	}
 
 	fmt.Println("Connection deleted successfully!")
 }
@@ -419,6 +485,41 @@ func editDefaultSettings() {
 		defaults.SSHKey = sshKey
 	}
 
+	fmt.Printf("Use native SSH client instead of the system ssh binary (current: %t): ", defaults.UseNativeSSH)
+	useNativeStr := readUserInput()
+	if useNativeStr != "" {
+		defaults.UseNativeSSH = useNativeStr == "y" || useNativeStr == "yes" || useNativeStr == "true"
+	}
+
+	fmt.Printf("known_hosts file (current: %s): ", defaults.KnownHostsFile)
+	knownHosts := readUserInput()
+	if knownHosts != "" {
+		defaults.KnownHostsFile = knownHosts
+	}
+
+	fmt.Printf("Host key algorithms, comma-separated (current: %s): ", strings.Join(defaults.HostKeyAlgorithms, ","))
+	hostKeyAlgorithms := readUserInput()
+	if hostKeyAlgorithms != "" {
+		defaults.HostKeyAlgorithms = splitCommaSeparated(hostKeyAlgorithms)
+	}
+
+	fmt.Printf("Max parallel hosts for multi-host commands (current: %d): ", defaults.MaxParallel)
+	maxParallelStr := readUserInput()
+	if maxParallelStr != "" {
+		maxParallel, err := strconv.Atoi(maxParallelStr)
+		if err != nil || maxParallel < 1 {
+			fmt.Println("Invalid value. Keeping current value.")
+		} else {
+			defaults.MaxParallel = maxParallel
+		}
+	}
+
+	fmt.Printf("External secret command, for schemes like op:// or pass:// (current: %s): ", defaults.SecretCommand)
+	secretCommand := readUserInput()
+	if secretCommand != "" {
+		defaults.SecretCommand = secretCommand
+	}
+
 	err = saveDefaults(defaults)
 	if err != nil {
 		fmt.Println("Error saving default settings:", err)
@@ -427,8 +528,22 @@ func editDefaultSettings() {
 	}
 }
 
+// connectToHost opens a session to the given connection, preferring the
+// native SSH client and falling back to the system ssh binary when the
+// user has opted out of it via Defaults.UseNativeSSH.
+func connectToHost(connection Connection, defaults Defaults) {
+	if defaults.UseNativeSSH {
+		if err := connectNative(connection, defaults); err != nil {
+			fmt.Println("Error connecting:", err)
+		}
+		return
+	}
+
+	executeSSHCommand(connection, defaults)
+}
+
 // Helper function to execute the SSH command based on the connection
-func executeSSHCommand(connection Connection) {
+func executeSSHCommand(connection Connection, defaults Defaults) {
 	sshPath := "ssh" // Default for Unix-like systems
 
 	if runtime.GOOS == "windows" {
@@ -442,13 +557,59 @@ func executeSSHCommand(connection Connection) {
 		}
 	}
 
-	cmd := exec.Command(sshPath, fmt.Sprintf("%s@%s", connection.Username, connection.Host), "-p", strconv.Itoa(connection.Port), "-i", connection.SSHKey)
+	keyPath, cleanupKey, err := sshKeyFileForExec(connection.SSHKey, defaults)
+	if err != nil {
+		log.Fatalf("Failed to resolve SSH key: %v", err)
+	}
+	defer cleanupKey()
+
+	cmd := exec.Command(sshPath, fmt.Sprintf("%s@%s", connection.Username, connection.Host), "-p", strconv.Itoa(connection.Port), "-i", keyPath)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	cmd.Stdin = os.Stdin
 
-	err := cmd.Run()
-	if err != nil {
+	if err := cmd.Run(); err != nil {
 		log.Fatalf("Failed to run SSH command: %v", err)
 	}
 }
+
+// sshKeyFileForExec returns a filesystem path suitable for the system ssh
+// binary's "-i" flag. A "file" scheme ref (including bare paths) is used
+// as-is; any other scheme is resolved and written to a short-lived 0600
+// temp file, since the system ssh binary has no notion of keyring/env/op
+// secret references. The returned cleanup func removes that temp file, if
+// one was created, and must be called once the ssh command has exited.
+func sshKeyFileForExec(ref SecretRef, defaults Defaults) (path string, cleanup func(), err error) {
+	noop := func() {}
+
+	scheme, identifier := ref.Scheme()
+	if scheme == "file" {
+		return identifier, noop, nil
+	}
+
+	key, err := ResolveSecret(context.Background(), ref, defaults)
+	if err != nil {
+		return "", noop, fmt.Errorf("resolving SSH key %s: %w", ref, err)
+	}
+	defer zeroBytes(key)
+
+	file, err := os.CreateTemp("", "ssm-key-*")
+	if err != nil {
+		return "", noop, fmt.Errorf("creating temp SSH key file: %w", err)
+	}
+
+	if err := file.Chmod(0600); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return "", noop, fmt.Errorf("securing temp SSH key file: %w", err)
+	}
+
+	if _, err := file.Write(key); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return "", noop, fmt.Errorf("writing temp SSH key file: %w", err)
+	}
+	file.Close()
+
+	return file.Name(), func() { os.Remove(file.Name()) }, nil
+}
@@ -0,0 +1,277 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+)
+
+// hostResult is one host's outcome from a multi-host command run.
+type hostResult struct {
+	Name      string
+	ExitCode  int
+	Duration  time.Duration
+	FirstLine string
+	Err       error
+}
+
+// splitCommaSeparated splits a comma-separated string into a trimmed,
+// non-empty slice. Used for both connection tags and lists like
+// Defaults.HostKeyAlgorithms.
+func splitCommaSeparated(input string) []string {
+	if strings.TrimSpace(input) == "" {
+		return nil
+	}
+
+	var tags []string
+	for _, tag := range strings.Split(input, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// runCommandOnMultipleHosts lets the user pick connections by index list,
+// "all", or a tag, then runs a shell command on each in parallel.
+func runCommandOnMultipleHosts() {
+	connections, err := loadConnections()
+	if err != nil {
+		fmt.Println("Error loading connections:", err)
+		return
+	}
+
+	if len(connections) == 0 {
+		fmt.Println("No connections found.")
+		return
+	}
+
+	printConnectionsTable(connections)
+
+	fmt.Print("Select hosts (e.g. '1,3,5', 'all', or 'tag:<name>'): ")
+	selection := readUserInput()
+
+	selected := selectConnections(connections, selection)
+	if len(selected) == 0 {
+		fmt.Println("No hosts matched that selection.")
+		return
+	}
+
+	fmt.Print("Command to run: ")
+	command := readUserInput()
+	if command == "" {
+		fmt.Println("No command entered.")
+		return
+	}
+
+	defaults, err := loadDefaults()
+	if err != nil {
+		fmt.Println("Error loading default settings:", err)
+		return
+	}
+
+	runDir, err := newRunLogDir()
+	if err != nil {
+		fmt.Println("Error creating run log directory:", err)
+		return
+	}
+
+	results := runOnHosts(selected, command, defaults, runDir)
+	printRunReport(results, runDir)
+}
+
+// selectConnections resolves a selection string (index list, "all", or
+// "tag:<name>") into the matching connections.
+func selectConnections(connections []Connection, selection string) []Connection {
+	selection = strings.TrimSpace(selection)
+
+	if selection == "all" {
+		return connections
+	}
+
+	if strings.HasPrefix(selection, "tag:") {
+		tag := strings.TrimPrefix(selection, "tag:")
+		var matched []Connection
+		for _, conn := range connections {
+			for _, connTag := range conn.Tags {
+				if connTag == tag {
+					matched = append(matched, conn)
+					break
+				}
+			}
+		}
+		return matched
+	}
+
+	var matched []Connection
+	for _, part := range strings.Split(selection, ",") {
+		index, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || index < 1 || index > len(connections) {
+			continue
+		}
+		matched = append(matched, connections[index-1])
+	}
+	return matched
+}
+
+// runOnHosts fans the command out over the selected connections with a
+// concurrency limit of defaults.MaxParallel, writing each host's full
+// output under runDir. Any interactive host key or password prompt
+// triggered along the way is serialized via stdinMu so concurrent dials
+// don't interleave their reads on the shared stdin.
+func runOnHosts(connections []Connection, command string, defaults Defaults, runDir string) []hostResult {
+	maxParallel := defaults.MaxParallel
+	if maxParallel < 1 {
+		maxParallel = 1
+	}
+
+	results := make([]hostResult, len(connections))
+	sem := make(chan struct{}, maxParallel)
+	var wg sync.WaitGroup
+
+	for i, connection := range connections {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, connection Connection) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = runOnHost(connection, command, defaults, runDir)
+		}(i, connection)
+	}
+
+	wg.Wait()
+	return results
+}
+
+// runOnHost opens a native SSH session to a single connection, runs
+// command, and records the result plus a full log file under runDir.
+func runOnHost(connection Connection, command string, defaults Defaults, runDir string) hostResult {
+	start := time.Now()
+	result := hostResult{Name: connection.Name}
+
+	client, err := dialNativeClient(connection, defaults)
+	if err != nil {
+		result.Err = err
+		result.ExitCode = -1
+		result.Duration = time.Since(start)
+		return result
+	}
+	defer client.Close()
+
+	session, err := client.NewSession()
+	if err != nil {
+		result.Err = err
+		result.ExitCode = -1
+		result.Duration = time.Since(start)
+		return result
+	}
+	defer session.Close()
+
+	output, runErr := session.CombinedOutput(command)
+	result.Duration = time.Since(start)
+	result.ExitCode = exitCodeFromError(runErr)
+	result.FirstLine = firstLine(output)
+
+	if logErr := writeHostLog(runDir, connection.Name, output); logErr != nil {
+		fmt.Printf("Warning: could not write log for %s: %v\n", connection.Name, logErr)
+	}
+
+	return result
+}
+
+// exitCodeFromError extracts the remote exit code from an ssh.Session run
+// error, returning 0 on success and -1 when the exit code isn't available.
+func exitCodeFromError(err error) int {
+	if err == nil {
+		return 0
+	}
+	if exitErr, ok := err.(interface{ ExitStatus() int }); ok {
+		return exitErr.ExitStatus()
+	}
+	return -1
+}
+
+func firstLine(output []byte) string {
+	line := strings.SplitN(string(output), "\n", 2)[0]
+	return strings.TrimSpace(line)
+}
+
+// newRunLogDir creates ~/.config/ssm/runs/<timestamp> for this run's logs.
+func newRunLogDir() (string, error) {
+	usr, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	timestamp := time.Now().Format("20060102-150405")
+	runDir := filepath.Join(usr, ".config", "ssm", "runs", timestamp)
+	return runDir, os.MkdirAll(runDir, os.ModePerm)
+}
+
+func writeHostLog(runDir, hostName string, output []byte) error {
+	return os.WriteFile(filepath.Join(runDir, sanitizeLogFilename(hostName)+".log"), output, 0644)
+}
+
+// sanitizeLogFilename flattens a connection name into a single safe path
+// segment so it can't escape runDir (via "..") or require directories that
+// were never created (via "/").
+func sanitizeLogFilename(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+
+	flattened := b.String()
+	if flattened == "" {
+		return "host"
+	}
+	return flattened
+}
+
+// printRunReport renders a colorized Host/ExitCode/Duration/FirstLine
+// table and points the user at the full logs on disk.
+func printRunReport(results []hostResult, runDir string) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Host", "Exit Code", "Duration", "First Line"})
+
+	okColor := color.New(color.FgGreen).SprintFunc()
+	failColor := color.New(color.FgRed).SprintFunc()
+
+	for _, result := range results {
+		exitCodeStr := strconv.Itoa(result.ExitCode)
+		firstLine := result.FirstLine
+		if result.Err != nil {
+			firstLine = result.Err.Error()
+		}
+
+		colorFn := okColor
+		if result.ExitCode != 0 {
+			colorFn = failColor
+		}
+
+		table.Append([]string{
+			colorFn(result.Name),
+			colorFn(exitCodeStr),
+			result.Duration.Round(time.Millisecond).String(),
+			firstLine,
+		})
+	}
+
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.Render()
+
+	fmt.Println("Full output logged under", runDir)
+}
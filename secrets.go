@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/zalando/go-keyring"
+)
+
+// SecretRef names a secret as "scheme://identifier", e.g.
+// "keyring://ssm/prod-db", "env://PROD_DB_KEY", "file:///home/x/.ssh/id_rsa",
+// or "op://vault/item/field". A bare path with no "scheme://" prefix is
+// treated as a file path, for backwards compatibility with plain SSHKey
+// values.
+type SecretRef string
+
+// Scheme splits the ref into its scheme and identifier. A ref with no
+// "scheme://" prefix is reported as scheme "file" with itself as the
+// identifier.
+func (r SecretRef) Scheme() (scheme, identifier string) {
+	ref := string(r)
+	if i := strings.Index(ref, "://"); i != -1 {
+		return ref[:i], ref[i+3:]
+	}
+	return "file", ref
+}
+
+// SecretResolver resolves a SecretRef to its underlying secret bytes.
+type SecretResolver interface {
+	Resolve(ctx context.Context, ref SecretRef) ([]byte, error)
+}
+
+// ResolveSecret dispatches a SecretRef to the resolver for its scheme.
+// External/unrecognized schemes (e.g. "op") are handed to the configured
+// external-command resolver so tools like the 1Password CLI, pass, or
+// bitwarden-cli work without a direct dependency on any of them.
+func ResolveSecret(ctx context.Context, ref SecretRef, defaults Defaults) ([]byte, error) {
+	if ref == "" {
+		return nil, fmt.Errorf("empty secret reference")
+	}
+
+	scheme, _ := ref.Scheme()
+
+	var resolver SecretResolver
+	switch scheme {
+	case "file":
+		resolver = fileSecretResolver{}
+	case "env":
+		resolver = envSecretResolver{}
+	case "keyring":
+		resolver = keyringSecretResolver{}
+	default:
+		resolver = commandSecretResolver{command: defaults.SecretCommand}
+	}
+
+	return resolver.Resolve(ctx, ref)
+}
+
+// zeroBytes overwrites a secret buffer after use.
+func zeroBytes(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
+
+// fileSecretResolver reads a secret from a plain file on disk.
+type fileSecretResolver struct{}
+
+func (fileSecretResolver) Resolve(_ context.Context, ref SecretRef) ([]byte, error) {
+	_, path := ref.Scheme()
+	return os.ReadFile(path)
+}
+
+// envSecretResolver reads a secret from an environment variable.
+type envSecretResolver struct{}
+
+func (envSecretResolver) Resolve(_ context.Context, ref SecretRef) ([]byte, error) {
+	_, name := ref.Scheme()
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return nil, fmt.Errorf("environment variable %s is not set", name)
+	}
+	return []byte(value), nil
+}
+
+// keyringSecretResolver reads a secret from the OS keyring. The identifier
+// is "service/user", e.g. "keyring://ssm/prod-db" looks up service "ssm",
+// user "prod-db".
+type keyringSecretResolver struct{}
+
+func (keyringSecretResolver) Resolve(_ context.Context, ref SecretRef) ([]byte, error) {
+	_, identifier := ref.Scheme()
+	service, user, ok := strings.Cut(identifier, "/")
+	if !ok {
+		return nil, fmt.Errorf("keyring ref must be keyring://service/user, got %q", identifier)
+	}
+
+	secret, err := keyring.Get(service, user)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read from OS keyring: %w", err)
+	}
+	return []byte(secret), nil
+}
+
+// commandSecretResolver shells out to a user-configured binary, passing
+// the full ref as its only argument and reading the secret from stdout.
+// This is how schemes like "op://" (1Password), "pass://", or
+// "bitwarden://" are supported without linking their CLIs directly.
+type commandSecretResolver struct {
+	command string
+}
+
+func (r commandSecretResolver) Resolve(ctx context.Context, ref SecretRef) ([]byte, error) {
+	if r.command == "" {
+		return nil, fmt.Errorf("no secret_command configured to resolve %q", ref)
+	}
+
+	cmd := exec.CommandContext(ctx, r.command, string(ref))
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("secret command failed: %w", err)
+	}
+
+	return bytes.TrimRight(stdout.Bytes(), "\n"), nil
+}
@@ -0,0 +1,542 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/sftp"
+)
+
+// browseFiles lets the user pick a connection and opens an interactive
+// SFTP prompt against it.
+func browseFiles() {
+	connections, err := loadConnections()
+	if err != nil {
+		fmt.Println("Error loading connections:", err)
+		return
+	}
+
+	if len(connections) == 0 {
+		fmt.Println("No connections found.")
+		return
+	}
+
+	printConnectionsTable(connections)
+
+	fmt.Print("Enter the number of the connection to browse, or 'b' to go back: ")
+	input := readUserInput()
+	if input == "b" {
+		return
+	}
+
+	index, err := strconv.Atoi(input)
+	if err != nil || index < 1 || index > len(connections) {
+		fmt.Println("Invalid selection.")
+		return
+	}
+
+	defaults, err := loadDefaults()
+	if err != nil {
+		fmt.Println("Error loading default settings:", err)
+		return
+	}
+
+	connection := connections[index-1]
+
+	client, err := dialNativeClient(connection, defaults)
+	if err != nil {
+		fmt.Println("Error connecting:", err)
+		return
+	}
+	defer client.Close()
+
+	sftpClient, err := sftp.NewClient(client)
+	if err != nil {
+		fmt.Println("Error starting SFTP session:", err)
+		return
+	}
+	defer sftpClient.Close()
+
+	runSFTPPrompt(sftpClient)
+}
+
+// runSFTPPrompt runs the ls/cd/get/put/rm/mkdir/rename/edit command loop.
+func runSFTPPrompt(client *sftp.Client) {
+	cwd, err := client.Getwd()
+	if err != nil {
+		cwd = "/"
+	}
+
+	fmt.Println("Entering SFTP mode. Type 'help' for commands, 'exit' to leave.")
+
+	for {
+		fmt.Printf("sftp %s> ", cwd)
+		line := readUserInput()
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		cmd, args := fields[0], fields[1:]
+
+		switch cmd {
+		case "exit", "quit":
+			return
+		case "help":
+			printSFTPHelp()
+		case "ls":
+			sftpList(client, cwd, args)
+		case "cd":
+			cwd = sftpChangeDir(client, cwd, args)
+		case "get":
+			sftpGet(client, cwd, args)
+		case "put":
+			sftpPut(client, cwd, args)
+		case "rm":
+			sftpRemove(client, cwd, args)
+		case "mkdir":
+			sftpMkdir(client, cwd, args)
+		case "rename":
+			sftpRename(client, cwd, args)
+		case "edit":
+			sftpEdit(client, cwd, args)
+		default:
+			fmt.Println("Unknown command. Type 'help' for commands.")
+		}
+	}
+}
+
+func printSFTPHelp() {
+	fmt.Println(`Commands:
+  ls                    list the current remote directory
+  cd <dir>              change the current remote directory
+  get <remote> [local]  download a file or directory (resumes partial files)
+  put <local> [remote]  upload a file or directory (resumes partial files)
+  rm <remote>           remove a remote file
+  mkdir <remote>        create a remote directory
+  rename <old> <new>    rename a remote file
+  edit <remote>         download, open in $EDITOR, upload on save
+  exit                  leave SFTP mode`)
+}
+
+// resolveRemote joins a possibly-relative remote path against cwd.
+func resolveRemote(cwd, remote string) string {
+	if path.IsAbs(remote) {
+		return path.Clean(remote)
+	}
+	return path.Join(cwd, remote)
+}
+
+func sftpList(client *sftp.Client, cwd string, args []string) {
+	dir := cwd
+	if len(args) > 0 {
+		dir = resolveRemote(cwd, args[0])
+	}
+
+	entries, err := client.ReadDir(dir)
+	if err != nil {
+		fmt.Println("Error listing directory:", err)
+		return
+	}
+
+	for _, entry := range entries {
+		kind := "-"
+		if entry.IsDir() {
+			kind = "d"
+		}
+		fmt.Printf("%s %10d  %s\n", kind, entry.Size(), entry.Name())
+	}
+}
+
+func sftpChangeDir(client *sftp.Client, cwd string, args []string) string {
+	if len(args) == 0 {
+		fmt.Println("Usage: cd <dir>")
+		return cwd
+	}
+
+	target := resolveRemote(cwd, args[0])
+	info, err := client.Stat(target)
+	if err != nil || !info.IsDir() {
+		fmt.Println("No such remote directory:", target)
+		return cwd
+	}
+
+	return target
+}
+
+func sftpMkdir(client *sftp.Client, cwd string, args []string) {
+	if len(args) == 0 {
+		fmt.Println("Usage: mkdir <remote>")
+		return
+	}
+
+	if err := client.Mkdir(resolveRemote(cwd, args[0])); err != nil {
+		fmt.Println("Error creating directory:", err)
+	}
+}
+
+func sftpRemove(client *sftp.Client, cwd string, args []string) {
+	if len(args) == 0 {
+		fmt.Println("Usage: rm <remote>")
+		return
+	}
+
+	if err := client.Remove(resolveRemote(cwd, args[0])); err != nil {
+		fmt.Println("Error removing file:", err)
+	}
+}
+
+func sftpRename(client *sftp.Client, cwd string, args []string) {
+	if len(args) != 2 {
+		fmt.Println("Usage: rename <old> <new>")
+		return
+	}
+
+	old := resolveRemote(cwd, args[0])
+	newName := resolveRemote(cwd, args[1])
+	if err := client.Rename(old, newName); err != nil {
+		fmt.Println("Error renaming file:", err)
+	}
+}
+
+// sftpGet downloads a remote file or, recursively, an entire remote
+// directory.
+func sftpGet(client *sftp.Client, cwd string, args []string) {
+	if len(args) == 0 {
+		fmt.Println("Usage: get <remote> [local]")
+		return
+	}
+
+	remotePath := resolveRemote(cwd, args[0])
+	localPath := path.Base(remotePath)
+	if len(args) > 1 {
+		localPath = args[1]
+	}
+
+	info, err := client.Stat(remotePath)
+	if err != nil {
+		fmt.Println("Error stating remote path:", err)
+		return
+	}
+
+	if info.IsDir() {
+		if err := sftpGetDir(client, remotePath, localPath); err != nil {
+			fmt.Println("Error downloading directory:", err)
+		}
+		return
+	}
+
+	if err := sftpGetFile(client, remotePath, localPath); err != nil {
+		fmt.Println("Error downloading file:", err)
+		return
+	}
+
+	fmt.Printf("Downloaded %s -> %s\n", remotePath, localPath)
+}
+
+// sftpGetDir recursively downloads a remote directory tree into localDir,
+// creating local directories as needed.
+func sftpGetDir(client *sftp.Client, remoteDir, localDir string) error {
+	if err := os.MkdirAll(localDir, 0755); err != nil {
+		return err
+	}
+
+	entries, err := client.ReadDir(remoteDir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		remotePath := path.Join(remoteDir, entry.Name())
+		localPath := filepath.Join(localDir, entry.Name())
+
+		if entry.IsDir() {
+			if err := sftpGetDir(client, remotePath, localPath); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := sftpGetFile(client, remotePath, localPath); err != nil {
+			return err
+		}
+		fmt.Printf("Downloaded %s -> %s\n", remotePath, localPath)
+	}
+
+	return nil
+}
+
+// sftpGetFile downloads a single remote file, resuming a partial local
+// copy by seeking both files to the local file's current size.
+func sftpGetFile(client *sftp.Client, remotePath, localPath string) error {
+	if dir := filepath.Dir(localPath); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+
+	remoteFile, err := client.Open(remotePath)
+	if err != nil {
+		return fmt.Errorf("opening remote file: %w", err)
+	}
+	defer remoteFile.Close()
+
+	localFile, err := os.OpenFile(localPath, os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("opening local file: %w", err)
+	}
+	defer localFile.Close()
+
+	offset, err := localFile.Seek(0, io.SeekEnd)
+	if err != nil {
+		return fmt.Errorf("seeking local file: %w", err)
+	}
+
+	if _, err := remoteFile.Seek(offset, io.SeekStart); err != nil {
+		return fmt.Errorf("seeking remote file: %w", err)
+	}
+
+	info, err := remoteFile.Stat()
+	if err != nil {
+		return fmt.Errorf("stating remote file: %w", err)
+	}
+
+	if err := copyWithProgress(localFile, remoteFile, info.Size()-offset, remotePath); err != nil {
+		return fmt.Errorf("downloading file: %w", err)
+	}
+
+	return nil
+}
+
+// sftpPut uploads a local file or, recursively, an entire local directory.
+func sftpPut(client *sftp.Client, cwd string, args []string) {
+	if len(args) == 0 {
+		fmt.Println("Usage: put <local> [remote]")
+		return
+	}
+
+	localPath := args[0]
+	remotePath := resolveRemote(cwd, path.Base(localPath))
+	if len(args) > 1 {
+		remotePath = resolveRemote(cwd, args[1])
+	}
+
+	info, err := os.Stat(localPath)
+	if err != nil {
+		fmt.Println("Error stating local path:", err)
+		return
+	}
+
+	if info.IsDir() {
+		if err := sftpPutDir(client, localPath, remotePath); err != nil {
+			fmt.Println("Error uploading directory:", err)
+		}
+		return
+	}
+
+	if err := sftpPutFile(client, localPath, remotePath); err != nil {
+		fmt.Println("Error uploading file:", err)
+		return
+	}
+
+	fmt.Printf("Uploaded %s -> %s\n", localPath, remotePath)
+}
+
+// sftpPutDir recursively uploads a local directory tree to remoteDir,
+// creating remote directories as needed.
+func sftpPutDir(client *sftp.Client, localDir, remoteDir string) error {
+	if err := client.MkdirAll(remoteDir); err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(localDir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		localPath := filepath.Join(localDir, entry.Name())
+		remotePath := path.Join(remoteDir, entry.Name())
+
+		if entry.IsDir() {
+			if err := sftpPutDir(client, localPath, remotePath); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := sftpPutFile(client, localPath, remotePath); err != nil {
+			return err
+		}
+		fmt.Printf("Uploaded %s -> %s\n", localPath, remotePath)
+	}
+
+	return nil
+}
+
+// sftpPutFile uploads a single local file, resuming a partial remote copy
+// by seeking both files to the remote file's current size.
+func sftpPutFile(client *sftp.Client, localPath, remotePath string) error {
+	localFile, err := os.Open(localPath)
+	if err != nil {
+		return fmt.Errorf("opening local file: %w", err)
+	}
+	defer localFile.Close()
+
+	if dir := path.Dir(remotePath); dir != "." {
+		if err := client.MkdirAll(dir); err != nil {
+			return fmt.Errorf("creating remote directory: %w", err)
+		}
+	}
+
+	remoteFile, err := client.OpenFile(remotePath, os.O_CREATE|os.O_WRONLY)
+	if err != nil {
+		return fmt.Errorf("opening remote file: %w", err)
+	}
+	defer remoteFile.Close()
+
+	remoteInfo, err := remoteFile.Stat()
+	offset := int64(0)
+	if err == nil {
+		offset = remoteInfo.Size()
+	}
+
+	localInfo, err := localFile.Stat()
+	if err != nil {
+		return fmt.Errorf("stating local file: %w", err)
+	}
+
+	if _, err := localFile.Seek(offset, io.SeekStart); err != nil {
+		return fmt.Errorf("seeking local file: %w", err)
+	}
+	if _, err := remoteFile.Seek(offset, io.SeekStart); err != nil {
+		return fmt.Errorf("seeking remote file: %w", err)
+	}
+
+	if err := copyWithProgress(remoteFile, localFile, localInfo.Size()-offset, remotePath); err != nil {
+		return fmt.Errorf("uploading file: %w", err)
+	}
+
+	return nil
+}
+
+// copyWithProgress copies src to dst, printing a carriage-return-updated
+// percentage as it goes.
+func copyWithProgress(dst io.Writer, src io.Reader, total int64, label string) error {
+	if total <= 0 {
+		_, err := io.Copy(dst, src)
+		fmt.Println()
+		return err
+	}
+
+	var copied int64
+	buf := make([]byte, 32*1024)
+	for {
+		n, readErr := src.Read(buf)
+		if n > 0 {
+			if _, err := dst.Write(buf[:n]); err != nil {
+				return err
+			}
+			copied += int64(n)
+			fmt.Printf("\r%s: %d%%", label, copied*100/total)
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return readErr
+		}
+	}
+	fmt.Println()
+	return nil
+}
+
+// sftpEdit downloads a remote file to a temp file, opens it in $EDITOR,
+// and uploads it back if the editor exits successfully after saving it
+// (the temp file's modification time or size changed).
+func sftpEdit(client *sftp.Client, cwd string, args []string) {
+	if len(args) == 0 {
+		fmt.Println("Usage: edit <remote>")
+		return
+	}
+
+	remotePath := resolveRemote(cwd, args[0])
+
+	temp, err := os.CreateTemp("", "ssm-edit-*-"+path.Base(remotePath))
+	if err != nil {
+		fmt.Println("Error creating temp file:", err)
+		return
+	}
+	tempPath := temp.Name()
+	defer os.Remove(tempPath)
+
+	remoteFile, err := client.Open(remotePath)
+	if err != nil {
+		temp.Close()
+		fmt.Println("Error opening remote file:", err)
+		return
+	}
+
+	if _, err := io.Copy(temp, remoteFile); err != nil {
+		remoteFile.Close()
+		temp.Close()
+		fmt.Println("Error downloading file:", err)
+		return
+	}
+	remoteFile.Close()
+	temp.Close()
+
+	before, err := os.Stat(tempPath)
+	if err != nil {
+		fmt.Println("Error stating temp file:", err)
+		return
+	}
+
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = "vi"
+	}
+
+	cmd := exec.Command(editor, tempPath)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		fmt.Println("Editor exited with an error, not uploading:", err)
+		return
+	}
+
+	after, err := os.Stat(tempPath)
+	if err != nil || (after.ModTime().Equal(before.ModTime()) && after.Size() == before.Size()) {
+		fmt.Println("No changes saved, not uploading.")
+		return
+	}
+
+	localFile, err := os.Open(tempPath)
+	if err != nil {
+		fmt.Println("Error reopening temp file:", err)
+		return
+	}
+	defer localFile.Close()
+
+	remoteWriter, err := client.OpenFile(remotePath, os.O_WRONLY|os.O_TRUNC|os.O_CREATE)
+	if err != nil {
+		fmt.Println("Error opening remote file for upload:", err)
+		return
+	}
+	defer remoteWriter.Close()
+
+	if _, err := io.Copy(remoteWriter, bufio.NewReader(localFile)); err != nil {
+		fmt.Println("Error uploading file:", err)
+		return
+	}
+
+	fmt.Println("Saved", remotePath)
+}
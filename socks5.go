@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// runDynamicForward listens locally and speaks just enough SOCKS5 (CONNECT,
+// no auth) to let any SOCKS-aware client tunnel its traffic through the ssh
+// connection, dialing each requested destination via client.Dial.
+func runDynamicForward(ctx context.Context, client *ssh.Client, tunnel Tunnel) {
+	bindAddr := fmt.Sprintf("%s:%d", tunnel.BindAddr, tunnel.BindPort)
+	listener, err := net.Listen("tcp", bindAddr)
+	if err != nil {
+		fmt.Printf("Warning: dynamic forward %s failed to bind: %v\n", bindAddr, err)
+		return
+	}
+	go closeOnDone(ctx, listener)
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		go handleSocks5Conn(client, conn)
+	}
+}
+
+// handleSocks5Conn performs the SOCKS5 handshake and CONNECT request on a
+// single accepted connection, then pipes bytes to the dialed destination.
+func handleSocks5Conn(client *ssh.Client, conn net.Conn) {
+	defer conn.Close()
+
+	if err := socks5Handshake(conn); err != nil {
+		return
+	}
+
+	dest, err := socks5ReadRequest(conn)
+	if err != nil {
+		socks5Reply(conn, 0x01) // general failure
+		return
+	}
+
+	remote, err := client.Dial("tcp", dest)
+	if err != nil {
+		socks5Reply(conn, 0x05) // connection refused
+		return
+	}
+	defer remote.Close()
+
+	if err := socks5Reply(conn, 0x00); err != nil {
+		return
+	}
+
+	pipe(conn, remote)
+}
+
+// socks5Handshake reads the client's method list and replies that no
+// authentication is required.
+func socks5Handshake(conn net.Conn) error {
+	header := make([]byte, 2)
+	if _, err := readFull(conn, header); err != nil {
+		return err
+	}
+	if header[0] != 0x05 {
+		return fmt.Errorf("unsupported SOCKS version %d", header[0])
+	}
+
+	methods := make([]byte, header[1])
+	if _, err := readFull(conn, methods); err != nil {
+		return err
+	}
+
+	_, err := conn.Write([]byte{0x05, 0x00})
+	return err
+}
+
+// socks5ReadRequest parses a CONNECT request and returns the "host:port"
+// destination it asked for.
+func socks5ReadRequest(conn net.Conn) (string, error) {
+	header := make([]byte, 4)
+	if _, err := readFull(conn, header); err != nil {
+		return "", err
+	}
+	if header[0] != 0x05 || header[1] != 0x01 { // version, CONNECT
+		return "", fmt.Errorf("unsupported SOCKS request")
+	}
+
+	var host string
+	switch header[3] {
+	case 0x01: // IPv4
+		addr := make([]byte, 4)
+		if _, err := readFull(conn, addr); err != nil {
+			return "", err
+		}
+		host = net.IP(addr).String()
+	case 0x03: // domain name
+		lenByte := make([]byte, 1)
+		if _, err := readFull(conn, lenByte); err != nil {
+			return "", err
+		}
+		domain := make([]byte, lenByte[0])
+		if _, err := readFull(conn, domain); err != nil {
+			return "", err
+		}
+		host = string(domain)
+	case 0x04: // IPv6
+		addr := make([]byte, 16)
+		if _, err := readFull(conn, addr); err != nil {
+			return "", err
+		}
+		host = net.IP(addr).String()
+	default:
+		return "", fmt.Errorf("unsupported SOCKS address type %d", header[3])
+	}
+
+	portBytes := make([]byte, 2)
+	if _, err := readFull(conn, portBytes); err != nil {
+		return "", err
+	}
+	port := binary.BigEndian.Uint16(portBytes)
+
+	return fmt.Sprintf("%s:%d", host, port), nil
+}
+
+// socks5Reply writes a SOCKS5 reply with the given status code and a dummy
+// bind address, which is sufficient for CONNECT-only clients.
+func socks5Reply(conn net.Conn, status byte) error {
+	reply := []byte{0x05, status, 0x00, 0x01, 0, 0, 0, 0, 0, 0}
+	_, err := conn.Write(reply)
+	return err
+}
+
+// readFull reads exactly len(buf) bytes from conn.
+func readFull(conn net.Conn, buf []byte) (int, error) {
+	total := 0
+	for total < len(buf) {
+		n, err := conn.Read(buf[total:])
+		total += n
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"net"
+	"testing"
+)
+
+func TestSocks5ReadRequestIPv4(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+
+	go client.Write([]byte{0x05, 0x01, 0x00, 0x01, 10, 0, 0, 1, 0x1F, 0x90}) // 10.0.0.1:8080
+
+	dest, err := socks5ReadRequest(server)
+	if err != nil {
+		t.Fatalf("socks5ReadRequest() error = %v", err)
+	}
+	if want := "10.0.0.1:8080"; dest != want {
+		t.Errorf("socks5ReadRequest() = %q, want %q", dest, want)
+	}
+}
+
+func TestSocks5ReadRequestDomain(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+
+	domain := "example.com"
+	req := append([]byte{0x05, 0x01, 0x00, 0x03, byte(len(domain))}, domain...)
+	req = append(req, 0x01, 0xBB) // port 443
+	go client.Write(req)
+
+	dest, err := socks5ReadRequest(server)
+	if err != nil {
+		t.Fatalf("socks5ReadRequest() error = %v", err)
+	}
+	if want := "example.com:443"; dest != want {
+		t.Errorf("socks5ReadRequest() = %q, want %q", dest, want)
+	}
+}
+
+func TestSocks5ReadRequestUnsupportedCommand(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+
+	go client.Write([]byte{0x05, 0x02, 0x00, 0x01, 10, 0, 0, 1, 0x1F, 0x90}) // BIND, not CONNECT
+
+	if _, err := socks5ReadRequest(server); err == nil {
+		t.Error("socks5ReadRequest() expected error for non-CONNECT command, got nil")
+	}
+}
+
+func TestSocks5Handshake(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+
+	go client.Write([]byte{0x05, 0x01, 0x00}) // version 5, one method: no-auth
+
+	done := make(chan error, 1)
+	go func() { done <- socks5Handshake(server) }()
+
+	reply := make([]byte, 2)
+	if _, err := readFull(client, reply); err != nil {
+		t.Fatalf("reading handshake reply: %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("socks5Handshake() error = %v", err)
+	}
+	if want := []byte{0x05, 0x00}; reply[0] != want[0] || reply[1] != want[1] {
+		t.Errorf("socks5Handshake() reply = %v, want %v", reply, want)
+	}
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/term"
+)
+
+// connectNative opens an interactive SSH session directly from this process
+// using golang.org/x/crypto/ssh, instead of shelling out to the system ssh
+// binary. It tries, in order: the connection's SSH key, the running
+// ssh-agent, and finally a password, taken from the connection's
+// PasswordRef when set and otherwise prompted for interactively.
+func connectNative(connection Connection, defaults Defaults) error {
+	client, err := dialNativeClient(connection, defaults)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	stopTunnels := startTunnels(client, connection.Tunnels)
+	defer stopTunnels()
+
+	session, err := client.NewSession()
+	if err != nil {
+		return fmt.Errorf("failed to create session: %w", err)
+	}
+	defer session.Close()
+
+	return runInteractiveSession(session)
+}
+
+// dialNativeClient builds the auth chain for a connection and dials it,
+// returning the underlying *ssh.Client so callers can layer sessions,
+// tunnels, or SFTP on top of the same connection.
+func dialNativeClient(connection Connection, defaults Defaults) (*ssh.Client, error) {
+	authMethods, err := buildAuthMethods(connection, defaults)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build auth methods: %w", err)
+	}
+
+	hostKeyCb, err := hostKeyCallback(defaults)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &ssh.ClientConfig{
+		User:              connection.Username,
+		Auth:              authMethods,
+		HostKeyCallback:   hostKeyCb,
+		HostKeyAlgorithms: defaults.HostKeyAlgorithms,
+	}
+
+	addr := fmt.Sprintf("%s:%d", connection.Host, connection.Port)
+	client, err := ssh.Dial("tcp", addr, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial %s: %w", addr, err)
+	}
+
+	return client, nil
+}
+
+// buildAuthMethods assembles the auth method chain for a connection: SSH
+// key first, then ssh-agent, then a password resolved from PasswordRef
+// or, when that is empty, read from an interactive prompt.
+func buildAuthMethods(connection Connection, defaults Defaults) ([]ssh.AuthMethod, error) {
+	var methods []ssh.AuthMethod
+
+	if connection.SSHKey != "" {
+		if method, err := publicKeyAuthMethod(connection.SSHKey, defaults); err == nil {
+			methods = append(methods, method)
+		} else {
+			fmt.Printf("Warning: could not load SSH key %s: %v\n", connection.SSHKey, err)
+		}
+	}
+
+	if method, err := agentAuthMethod(); err == nil {
+		methods = append(methods, method)
+	}
+
+	methods = append(methods, ssh.PasswordCallback(func() (string, error) {
+		if connection.PasswordRef != "" {
+			password, err := ResolveSecret(context.Background(), connection.PasswordRef, defaults)
+			if err != nil {
+				return "", err
+			}
+			defer zeroBytes(password)
+			return string(password), nil
+		}
+
+		stdinMu.Lock()
+		defer stdinMu.Unlock()
+
+		fmt.Printf("%s@%s's password: ", connection.Username, connection.Host)
+		password, err := term.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Println()
+		return string(password), err
+	}))
+
+	return methods, nil
+}
+
+// publicKeyAuthMethod resolves the connection's SSH key secret reference,
+// prompting for a passphrase if the key is encrypted, and zeroes the key
+// material once the signer has been built.
+func publicKeyAuthMethod(ref SecretRef, defaults Defaults) (ssh.AuthMethod, error) {
+	key, err := ResolveSecret(context.Background(), ref, defaults)
+	if err != nil {
+		return nil, err
+	}
+	defer zeroBytes(key)
+
+	signer, err := ssh.ParsePrivateKey(key)
+	if err != nil {
+		if _, ok := err.(*ssh.PassphraseMissingError); ok {
+			passphrase, readErr := func() ([]byte, error) {
+				stdinMu.Lock()
+				defer stdinMu.Unlock()
+
+				fmt.Printf("Enter passphrase for key %s: ", ref)
+				passphrase, readErr := term.ReadPassword(int(os.Stdin.Fd()))
+				fmt.Println()
+				return passphrase, readErr
+			}()
+			if readErr != nil {
+				return nil, readErr
+			}
+			defer zeroBytes(passphrase)
+			signer, err = ssh.ParsePrivateKeyWithPassphrase(key, passphrase)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return ssh.PublicKeys(signer), nil
+}
+
+// agentAuthMethod connects to the running ssh-agent over SSH_AUTH_SOCK.
+func agentAuthMethod() (ssh.AuthMethod, error) {
+	socket := os.Getenv("SSH_AUTH_SOCK")
+	if socket == "" {
+		return nil, fmt.Errorf("SSH_AUTH_SOCK not set")
+	}
+
+	conn, err := net.Dial("unix", socket)
+	if err != nil {
+		return nil, err
+	}
+
+	agentClient := agent.NewClient(conn)
+	return ssh.PublicKeysCallback(agentClient.Signers), nil
+}
+
+// runInteractiveSession requests a PTY sized to the current terminal, wires
+// up stdio, and runs the user's shell, keeping the PTY in sync on resize.
+func runInteractiveSession(session *ssh.Session) error {
+	fd := int(os.Stdin.Fd())
+
+	oldState, err := term.MakeRaw(fd)
+	if err != nil {
+		return fmt.Errorf("failed to put terminal in raw mode: %w", err)
+	}
+	defer term.Restore(fd, oldState)
+
+	width, height, err := term.GetSize(fd)
+	if err != nil {
+		width, height = 80, 24
+	}
+
+	termType := os.Getenv("TERM")
+	if termType == "" {
+		termType = "xterm-256color"
+	}
+
+	modes := ssh.TerminalModes{
+		ssh.ECHO:          1,
+		ssh.TTY_OP_ISPEED: 14400,
+		ssh.TTY_OP_OSPEED: 14400,
+	}
+	if err := session.RequestPty(termType, height, width, modes); err != nil {
+		return fmt.Errorf("failed to request pty: %w", err)
+	}
+
+	watchWindowSize(session, fd)
+
+	session.Stdin = os.Stdin
+	session.Stdout = os.Stdout
+	session.Stderr = os.Stderr
+
+	if err := session.Shell(); err != nil {
+		return fmt.Errorf("failed to start shell: %w", err)
+	}
+
+	return session.Wait()
+}
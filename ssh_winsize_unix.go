@@ -0,0 +1,29 @@
+//go:build !windows
+
+package main
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/term"
+)
+
+// watchWindowSize listens for SIGWINCH and forwards the new terminal size
+// to the remote session so full-screen programs redraw correctly on resize.
+func watchWindowSize(session *ssh.Session, fd int) {
+	resize := make(chan os.Signal, 1)
+	signal.Notify(resize, syscall.SIGWINCH)
+
+	go func() {
+		for range resize {
+			width, height, err := term.GetSize(fd)
+			if err != nil {
+				continue
+			}
+			_ = session.WindowChange(height, width)
+		}
+	}()
+}
@@ -0,0 +1,8 @@
+//go:build windows
+
+package main
+
+import "golang.org/x/crypto/ssh"
+
+// watchWindowSize is a no-op on Windows, which has no SIGWINCH equivalent.
+func watchWindowSize(session *ssh.Session, fd int) {}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// sshConfigHost holds the fields importSSHConfigHosts cares about from a
+// single Host block in ~/.ssh/config.
+type sshConfigHost struct {
+	Alias        string
+	HostName     string
+	Port         int
+	User         string
+	IdentityFile string
+}
+
+// importSSHConfigHosts parses ~/.ssh/config, turns each concrete Host block
+// into a Connection, and appends any new ones to connections.json.
+func importSSHConfigHosts() {
+	defaults, err := loadDefaults()
+	if err != nil {
+		fmt.Println("Error loading default settings:", err)
+		return
+	}
+
+	path := filepath.Join(os.Getenv("HOME"), ".ssh", "config")
+	hosts, err := parseSSHConfig(path)
+	if err != nil {
+		fmt.Println("Error reading SSH config:", err)
+		return
+	}
+
+	if len(hosts) == 0 {
+		fmt.Println("No hosts found in", path)
+		return
+	}
+
+	connections, err := loadConnections()
+	if err != nil {
+		fmt.Println("Error loading connections:", err)
+		return
+	}
+
+	existing := make(map[string]bool, len(connections))
+	for _, conn := range connections {
+		existing[conn.Name] = true
+	}
+
+	imported := 0
+	for _, host := range hosts {
+		if existing[host.Alias] {
+			continue
+		}
+
+		port := defaults.Port
+		if host.Port != 0 {
+			port = host.Port
+		}
+
+		username := defaults.Username
+		if host.User != "" {
+			username = host.User
+		}
+
+		sshKey := defaults.SSHKey
+		if host.IdentityFile != "" {
+			sshKey = host.IdentityFile
+		}
+
+		connections = append(connections, Connection{
+			Name:     host.Alias,
+			Host:     host.HostName,
+			Port:     port,
+			Username: username,
+			SSHKey:   SecretRef(sshKey),
+		})
+		imported++
+	}
+
+	if imported == 0 {
+		fmt.Println("No new hosts to import; all aliases already exist.")
+		return
+	}
+
+	if err := saveConnections(connections); err != nil {
+		fmt.Println("Error saving connections:", err)
+		return
+	}
+
+	fmt.Printf("Imported %d host(s) from %s\n", imported, path)
+}
+
+// parseSSHConfig reads a Host/Match-aware OpenSSH config file and returns
+// one sshConfigHost per concrete (non-wildcard) Host alias.
+func parseSSHConfig(path string) ([]sshConfigHost, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var hosts []sshConfigHost
+	var current []*sshConfigHost
+	inMatchBlock := false
+
+	flush := func() {
+		for _, host := range current {
+			hosts = append(hosts, *host)
+		}
+		current = nil
+	}
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, ok := splitSSHConfigLine(line)
+		if !ok {
+			continue
+		}
+
+		switch strings.ToLower(key) {
+		case "host":
+			flush()
+			inMatchBlock = false
+
+			for _, pattern := range strings.Fields(value) {
+				if strings.ContainsAny(pattern, "*?") {
+					continue
+				}
+				current = append(current, &sshConfigHost{Alias: pattern})
+			}
+		case "match":
+			flush()
+			inMatchBlock = true
+		default:
+			if len(current) == 0 || inMatchBlock {
+				continue
+			}
+			for _, host := range current {
+				switch strings.ToLower(key) {
+				case "hostname":
+					host.HostName = value
+				case "port":
+					if port, err := strconv.Atoi(value); err == nil {
+						host.Port = port
+					}
+				case "user":
+					host.User = value
+				case "identityfile":
+					host.IdentityFile = expandHome(value)
+				}
+			}
+		}
+	}
+	flush()
+
+	return hosts, scanner.Err()
+}
+
+// splitSSHConfigLine splits a config line into its keyword and argument,
+// accepting "Key Value", "Key\tValue", and "Key=Value" forms.
+func splitSSHConfigLine(line string) (string, string, bool) {
+	line = strings.Replace(line, "=", " ", 1)
+
+	sepIndex := strings.IndexFunc(line, unicode.IsSpace)
+	if sepIndex == -1 {
+		return "", "", false
+	}
+
+	key := line[:sepIndex]
+	value := strings.TrimSpace(line[sepIndex:])
+	if key == "" || value == "" {
+		return "", "", false
+	}
+	return key, strings.Trim(value, `"`), true
+}
+
+// expandHome replaces a leading "~" with the user's home directory.
+func expandHome(path string) string {
+	if strings.HasPrefix(path, "~") {
+		return filepath.Join(os.Getenv("HOME"), strings.TrimPrefix(path, "~"))
+	}
+	return path
+}
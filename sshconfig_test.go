@@ -0,0 +1,69 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSplitSSHConfigLine(t *testing.T) {
+	cases := []struct {
+		name      string
+		line      string
+		wantKey   string
+		wantValue string
+		wantOK    bool
+	}{
+		{"space separated", "HostName 10.0.0.1", "HostName", "10.0.0.1", true},
+		{"tab separated", "HostName\t10.0.0.1", "HostName", "10.0.0.1", true},
+		{"equals separated", "HostName=10.0.0.1", "HostName", "10.0.0.1", true},
+		{"multiple patterns", "Host web1 web2", "Host", "web1 web2", true},
+		{"quoted value", `IdentityFile "~/.ssh/id_ed25519"`, "IdentityFile", "~/.ssh/id_ed25519", true},
+		{"no separator", "HostName", "", "", false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			key, value, ok := splitSSHConfigLine(c.line)
+			if ok != c.wantOK || key != c.wantKey || value != c.wantValue {
+				t.Errorf("splitSSHConfigLine(%q) = (%q, %q, %v), want (%q, %q, %v)",
+					c.line, key, value, ok, c.wantKey, c.wantValue, c.wantOK)
+			}
+		})
+	}
+}
+
+func TestParseSSHConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config")
+	contents := "Host web1 web2\n\tHostName\t10.0.0.1\n\tPort 2222\n\nHost bastion *\n\tHostName bastion.example.com\n\tUser ops\n"
+	if err := os.WriteFile(path, []byte(contents), 0600); err != nil {
+		t.Fatalf("writing test config: %v", err)
+	}
+
+	hosts, err := parseSSHConfig(path)
+	if err != nil {
+		t.Fatalf("parseSSHConfig() error = %v", err)
+	}
+
+	want := map[string]sshConfigHost{
+		"web1":    {Alias: "web1", HostName: "10.0.0.1", Port: 2222},
+		"web2":    {Alias: "web2", HostName: "10.0.0.1", Port: 2222},
+		"bastion": {Alias: "bastion", HostName: "bastion.example.com", User: "ops"},
+	}
+
+	if len(hosts) != len(want) {
+		t.Fatalf("parseSSHConfig() returned %d hosts, want %d (%+v)", len(hosts), len(want), hosts)
+	}
+
+	for _, host := range hosts {
+		expected, ok := want[host.Alias]
+		if !ok {
+			t.Errorf("unexpected alias %q", host.Alias)
+			continue
+		}
+		if host != expected {
+			t.Errorf("host %q = %+v, want %+v", host.Alias, host, expected)
+		}
+	}
+}
@@ -0,0 +1,264 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"golang.org/x/crypto/ssh"
+)
+
+// Tunnel describes a single local, remote, or dynamic (SOCKS5) port forward
+// to set up when a connection is opened.
+type Tunnel struct {
+	Type     string `json:"type"` // "local", "remote", or "dynamic"
+	BindAddr string `json:"bind_addr"`
+	BindPort int    `json:"bind_port"`
+	DestHost string `json:"dest_host,omitempty"`
+	DestPort int    `json:"dest_port,omitempty"`
+}
+
+// startTunnels spins up a goroutine per configured tunnel and returns a
+// function that tears them all down. Forwards that fail to bind log a
+// warning but don't prevent the session from starting.
+func startTunnels(client *ssh.Client, tunnels []Tunnel) func() {
+	if len(tunnels) == 0 {
+		return func() {}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	for _, tunnel := range tunnels {
+		switch tunnel.Type {
+		case "local":
+			go runLocalForward(ctx, client, tunnel)
+		case "remote":
+			go runRemoteForward(ctx, client, tunnel)
+		case "dynamic":
+			go runDynamicForward(ctx, client, tunnel)
+		default:
+			fmt.Printf("Warning: unknown tunnel type %q, skipping\n", tunnel.Type)
+		}
+	}
+
+	printActiveTunnels(tunnels)
+
+	return cancel
+}
+
+// runLocalForward listens locally and pipes each accepted connection
+// through the ssh client to the remote destination.
+func runLocalForward(ctx context.Context, client *ssh.Client, tunnel Tunnel) {
+	bindAddr := fmt.Sprintf("%s:%d", tunnel.BindAddr, tunnel.BindPort)
+	listener, err := net.Listen("tcp", bindAddr)
+	if err != nil {
+		fmt.Printf("Warning: local forward %s failed to bind: %v\n", bindAddr, err)
+		return
+	}
+	go closeOnDone(ctx, listener)
+
+	dest := fmt.Sprintf("%s:%d", tunnel.DestHost, tunnel.DestPort)
+	for {
+		local, err := listener.Accept()
+		if err != nil {
+			return
+		}
+
+		go func() {
+			defer local.Close()
+			remote, err := client.Dial("tcp", dest)
+			if err != nil {
+				fmt.Printf("Warning: local forward to %s failed: %v\n", dest, err)
+				return
+			}
+			defer remote.Close()
+			pipe(local, remote)
+		}()
+	}
+}
+
+// runRemoteForward asks the remote server to listen and pipes each
+// accepted connection back to a local destination.
+func runRemoteForward(ctx context.Context, client *ssh.Client, tunnel Tunnel) {
+	bindAddr := fmt.Sprintf("%s:%d", tunnel.BindAddr, tunnel.BindPort)
+	listener, err := client.Listen("tcp", bindAddr)
+	if err != nil {
+		fmt.Printf("Warning: remote forward %s failed to bind: %v\n", bindAddr, err)
+		return
+	}
+	go closeOnDone(ctx, listener)
+
+	dest := fmt.Sprintf("%s:%d", tunnel.DestHost, tunnel.DestPort)
+	for {
+		remote, err := listener.Accept()
+		if err != nil {
+			return
+		}
+
+		go func() {
+			defer remote.Close()
+			local, err := net.Dial("tcp", dest)
+			if err != nil {
+				fmt.Printf("Warning: remote forward to %s failed: %v\n", dest, err)
+				return
+			}
+			defer local.Close()
+			pipe(local, remote)
+		}()
+	}
+}
+
+// closeOnDone closes listener once ctx is cancelled, unblocking its Accept loop.
+func closeOnDone(ctx context.Context, listener io.Closer) {
+	<-ctx.Done()
+	listener.Close()
+}
+
+// pipe copies bytes between two connections in both directions until
+// either side closes.
+func pipe(a, b net.Conn) {
+	done := make(chan struct{}, 2)
+	go func() {
+		io.Copy(a, b)
+		done <- struct{}{}
+	}()
+	go func() {
+		io.Copy(b, a)
+		done <- struct{}{}
+	}()
+	<-done
+}
+
+// printActiveTunnels renders a colorized table of the tunnels that were
+// just started for a connection.
+func printActiveTunnels(tunnels []Tunnel) {
+	typeColor := color.New(color.FgCyan).SprintFunc()
+	addrColor := color.New(color.FgGreen).SprintFunc()
+	destColor := color.New(color.FgYellow).SprintFunc()
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Type", "Bind", "Destination"})
+
+	for _, tunnel := range tunnels {
+		dest := "-"
+		if tunnel.Type != "dynamic" {
+			dest = fmt.Sprintf("%s:%d", tunnel.DestHost, tunnel.DestPort)
+		}
+		table.Append([]string{
+			typeColor(tunnel.Type),
+			addrColor(fmt.Sprintf("%s:%d", tunnel.BindAddr, tunnel.BindPort)),
+			destColor(dest),
+		})
+	}
+
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.Render()
+}
+
+// manageTunnels lets the user add or remove tunnels on an existing connection.
+func manageTunnels() {
+	connections, err := loadConnections()
+	if err != nil {
+		fmt.Println("Error loading connections:", err)
+		return
+	}
+
+	if len(connections) == 0 {
+		fmt.Println("No connections found.")
+		return
+	}
+
+	printConnectionsTable(connections)
+
+	fmt.Print("Enter the number of the connection to manage tunnels for, or 'b' to go back: ")
+	input := readUserInput()
+	if input == "b" {
+		return
+	}
+
+	index, err := strconv.Atoi(input)
+	if err != nil || index < 1 || index > len(connections) {
+		fmt.Println("Invalid selection.")
+		return
+	}
+
+	connection := &connections[index-1]
+
+	fmt.Println(`
+1. Add tunnel
+2. Remove tunnel
+3. Back
+Please enter your choice:`)
+
+	switch readUserInput() {
+	case "1":
+		addTunnel(connection)
+	case "2":
+		removeTunnel(connection)
+	default:
+		return
+	}
+
+	if err := saveConnections(connections); err != nil {
+		fmt.Println("Error saving connections:", err)
+	}
+}
+
+// addTunnel prompts for a new tunnel's fields and appends it to the connection.
+func addTunnel(connection *Connection) {
+	fmt.Print("Type (local/remote/dynamic): ")
+	tunnelType := readUserInput()
+
+	fmt.Print("Bind address (e.g. 127.0.0.1): ")
+	bindAddr := readUserInput()
+
+	fmt.Print("Bind port: ")
+	bindPort, err := strconv.Atoi(readUserInput())
+	if err != nil {
+		fmt.Println("Invalid bind port.")
+		return
+	}
+
+	tunnel := Tunnel{Type: tunnelType, BindAddr: bindAddr, BindPort: bindPort}
+
+	if tunnelType != "dynamic" {
+		fmt.Print("Destination host: ")
+		tunnel.DestHost = readUserInput()
+
+		fmt.Print("Destination port: ")
+		destPort, err := strconv.Atoi(readUserInput())
+		if err != nil {
+			fmt.Println("Invalid destination port.")
+			return
+		}
+		tunnel.DestPort = destPort
+	}
+
+	connection.Tunnels = append(connection.Tunnels, tunnel)
+	fmt.Println("Tunnel added successfully!")
+}
+
+// removeTunnel lists the connection's tunnels and deletes the chosen one.
+func removeTunnel(connection *Connection) {
+	if len(connection.Tunnels) == 0 {
+		fmt.Println("No tunnels configured for this connection.")
+		return
+	}
+
+	printActiveTunnels(connection.Tunnels)
+
+	fmt.Print("Enter the number of the tunnel to remove: ")
+	index, err := strconv.Atoi(readUserInput())
+	if err != nil || index < 1 || index > len(connection.Tunnels) {
+		fmt.Println("Invalid number.")
+		return
+	}
+
+	connection.Tunnels = append(connection.Tunnels[:index-1], connection.Tunnels[index:]...)
+	fmt.Println("Tunnel removed successfully!")
+}